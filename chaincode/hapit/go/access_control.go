@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// habitCreatorAttribute is the enrollment certificate attribute that must be
+// present and set to "true" for an identity to be allowed to create habits.
+const habitCreatorAttribute = "habit.creator"
+
+// callerCommonName returns the x509 Subject Common Name of the identity that
+// submitted the current transaction, which we use to match callers against
+// the plain person names stored on a Habit's Owner/Attendees fields.
+func callerCommonName(ctx contractapi.TransactionContextInterface) (string, error) {
+	cert, err := cid.GetX509Certificate(ctx.GetStub())
+	if err != nil {
+		return "", fmt.Errorf("failed to read caller identity: %s", err.Error())
+	}
+
+	return cert.Subject.CommonName, nil
+}
+
+// authorizeHabitCreation ensures the calling identity's enrollment certificate
+// carries the habit.creator=true attribute.
+func authorizeHabitCreation(ctx contractapi.TransactionContextInterface) error {
+	value, ok, err := cid.GetAttributeValue(ctx.GetStub(), habitCreatorAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to read caller attributes: %s", err.Error())
+	}
+	if !ok || value != "true" {
+		return &PermissionError{Message: fmt.Sprintf("caller is not authorized to create habits: missing %s=true attribute", habitCreatorAttribute)}
+	}
+
+	return nil
+}
+
+// authorizeOwnerChange ensures only the current owner of a habit may reassign it.
+func authorizeOwnerChange(ctx contractapi.TransactionContextInterface, habit *Habit) error {
+	caller, err := callerCommonName(ctx)
+	if err != nil {
+		return err
+	}
+	if caller != habit.Owner {
+		return &PermissionError{Message: fmt.Sprintf("caller %s is not the owner of this habit", caller)}
+	}
+
+	return nil
+}
+
+// authorizeAttendeeChange ensures only the owner or an existing attendee of a
+// habit may add a new attendee to it.
+func authorizeAttendeeChange(ctx contractapi.TransactionContextInterface, habit *Habit, attendees []string) error {
+	caller, err := callerCommonName(ctx)
+	if err != nil {
+		return err
+	}
+	if caller == habit.Owner {
+		return nil
+	}
+	for _, attendee := range attendees {
+		if caller == attendee {
+			return nil
+		}
+	}
+
+	return &PermissionError{Message: fmt.Sprintf("caller %s is neither the owner nor an attendee of this habit", caller)}
+}
@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Event names emitted for habit lifecycle changes so that external clients
+// can subscribe to them via a block or chaincode event listener.
+const (
+	habitCreatedEvent       = "HabitCreated"
+	habitOwnerChangedEvent  = "HabitOwnerChanged"
+	habitAttendeeAddedEvent = "HabitAttendeeAdded"
+)
+
+// HabitEvent is the payload emitted for every habit lifecycle event. Previous
+// and Current hold plain values for public fields (such as a habit's owner),
+// but for data that lives in a private data collection they hold a
+// hex-encoded hash instead, since chaincode events are broadcast to every
+// peer on the channel regardless of collection membership. A single
+// chaincode invocation can only set one event, so each handler that emits
+// one must do so exactly once, as its last step.
+type HabitEvent struct {
+	HabitID   string `json:"habitID"`
+	Previous  string `json:"previous,omitempty"`
+	Current   string `json:"current"`
+	TxID      string `json:"txID"`
+	Timestamp string `json:"timestamp"`
+}
+
+// emitHabitEvent sets a chaincode event describing one habit lifecycle
+// change. It must be called at most once per transaction.
+func emitHabitEvent(ctx contractapi.TransactionContextInterface, name, habitID, previous, current string) error {
+	txTimestamp, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return err
+	}
+
+	event := HabitEvent{
+		HabitID:   habitID,
+		Previous:  previous,
+		Current:   current,
+		TxID:      ctx.GetStub().GetTxID(),
+		Timestamp: txTimestamp.AsTime().UTC().Format(time.RFC3339),
+	}
+
+	eventAsBytes, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().SetEvent(name, eventAsBytes)
+}
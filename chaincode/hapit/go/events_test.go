@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHabitEmitsHabitCreated(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+
+	name, payload := stub.Event()
+	require.Equal(t, habitCreatedEvent, name)
+
+	var event HabitEvent
+	require.NoError(t, json.Unmarshal(payload, &event))
+	require.Equal(t, "HABIT0", event.HabitID)
+	require.Equal(t, "Kathy", event.Current)
+	require.Empty(t, event.Previous)
+}
+
+func TestChangeHabitOwnerEmitsHabitOwnerChanged(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+	require.NoError(t, contract.ChangeHabitOwner(ctx, "HABIT0", "Ruby"))
+
+	name, payload := stub.Event()
+	require.Equal(t, habitOwnerChangedEvent, name)
+
+	var event HabitEvent
+	require.NoError(t, json.Unmarshal(payload, &event))
+	require.Equal(t, "HABIT0", event.HabitID)
+	require.Equal(t, "Kathy", event.Previous)
+	require.Equal(t, "Ruby", event.Current)
+}
+
+func TestChangeHabitAttendeesEmitsHabitAttendeeAdded(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+	require.NoError(t, contract.ChangeHabitAttendees(ctx, "HABIT0", "Kimi"))
+
+	name, payload := stub.Event()
+	require.Equal(t, habitAttendeeAddedEvent, name)
+
+	var event HabitEvent
+	require.NoError(t, json.Unmarshal(payload, &event))
+	require.Equal(t, "HABIT0", event.HabitID)
+	require.NotContains(t, event.Previous, "Kimi")
+	require.NotContains(t, event.Current, "Kimi")
+	require.NotEmpty(t, event.Current)
+	require.NotEqual(t, event.Previous, event.Current)
+}
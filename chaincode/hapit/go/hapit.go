@@ -24,214 +24,274 @@
 
 package main
 
-/* Imports
- * 4 utility libraries for formatting, handling bytes, reading and writing JSON, and string manipulation
- * 2 specific Hyperledger Fabric specific libraries for Smart Contracts
- */
 import (
-	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"strconv"
 
-	"github.com/hyperledger/fabric/core/chaincode/shim"
-	sc "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 )
 
-// Define the Smart Contract structure
+// SmartContract provides functions for managing habits
 type SmartContract struct {
+	contractapi.Contract
 }
 
-// Define the habit structure, with 4 properties.  Structure tags are used by encoding/json library
+// Habit describes the public details of a habit. The attendee list and any
+// notes are sensitive and are kept out of this struct; see
+// HabitPrivateDetails and the habitAttendeesCollection.
 type Habit struct {
-	Name      string   `json:"name"`
-	Type      string   `json:"type"`
-	Attendees []string `json:"attendees"`
-	Owner     string   `json:"owner"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Owner string `json:"owner"`
 }
 
+// Person describes basic details of a person participating in habits
 type Person struct {
 	Name string `json:"name"`
 	Age  int    `json:"age"`
 }
 
-/*
- * The Init method is called when the Smart Contract "fabcar" is instantiated by the blockchain network
- * Best practice is to have any Ledger initialization in separate function -- see initLedger()
- */
-func (s *SmartContract) Init(APIstub shim.ChaincodeStubInterface) sc.Response {
-	return shim.Success(nil)
-}
-
-/*
- * The Invoke method is called as a result of an application request to run the Smart Contract "fabcar"
- * The calling application program has also specified the particular smart contract function to be called, with arguments
- */
-func (s *SmartContract) Invoke(APIstub shim.ChaincodeStubInterface) sc.Response {
-
-	// Retrieve the requested Smart Contract function and arguments
-	function, args := APIstub.GetFunctionAndParameters()
-	// Route to the appropriate handler function to interact with the ledger appropriately
-	if function == "queryHabit" {
-		return s.queryHabit(APIstub, args)
-	} else if function == "initLedger" {
-		return s.initLedger(APIstub)
-	} else if function == "createHabit" {
-		return s.createHabit(APIstub, args)
-	} else if function == "queryAllHabits" {
-		return s.queryAllHabits(APIstub)
-	} else if function == "changeHabitOwner" {
-		return s.changeHabitOwner(APIstub, args)
-	} else if function == "changeHabitAttendees" {
-		return s.changeHabitAttendees(APIstub, args)
-	}
-
-	return shim.Error("Invalid Smart Contract function name.")
-}
+// InitLedger adds a base set of habits and people to the ledger
+func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface) error {
+	habits := []struct {
+		Habit
+		Attendees []string
+	}{
+		{Habit{Name: "Running", Type: "Health", Owner: "Kathy"}, []string{"Ruby", "Kathy"}},
+		{Habit{Name: "English", Type: "Learning", Owner: "Kathy"}, []string{"Kathy"}},
+		{Habit{Name: "Workout", Type: "Health", Owner: "Kimi"}, []string{"Kimi", "Rocky"}},
+		{Habit{Name: "bark", Type: "Nature", Owner: "Rocky"}, []string{"Ruby", "Rocky"}},
+		{Habit{Name: "Blockchain", Type: "Learning", Owner: "Kimi"}, []string{"Kimi", "Ruby", "Rocky"}},
+	}
 
-func (s *SmartContract) queryHabit(APIstub shim.ChaincodeStubInterface, args []string) sc.Response {
+	for i, entry := range habits {
+		habitID := fmt.Sprintf("HABIT%d", i)
 
-	if len(args) != 1 {
-		return shim.Error("Incorrect number of arguments. Expecting 1")
-	}
+		habitAsBytes, err := json.Marshal(entry.Habit)
+		if err != nil {
+			return err
+		}
+		if err := ctx.GetStub().PutState(habitID, habitAsBytes); err != nil {
+			return fmt.Errorf("failed to put habit to world state: %s", err.Error())
+		}
 
-	carAsBytes, _ := APIstub.GetState(args[0])
-	return shim.Success(carAsBytes)
-}
+		detailsAsBytes, err := json.Marshal(HabitPrivateDetails{Attendees: entry.Attendees})
+		if err != nil {
+			return err
+		}
+		if err := ctx.GetStub().PutPrivateData(habitAttendeesCollection, habitID, detailsAsBytes); err != nil {
+			return fmt.Errorf("failed to put habit attendees to the private collection: %s", err.Error())
+		}
 
-func (s *SmartContract) initLedger(APIstub shim.ChaincodeStubInterface) sc.Response {
-	habits := []Habit{
-		Habit{Name: "Running", Type: "Health", Attendees: []string{"Ruby", "Kathy"}, Owner: "Kathy"},
-		Habit{Name: "English", Type: "Learning", Attendees: []string{"Kathy"}, Owner: "Kathy"},
-		Habit{Name: "Workout", Type: "Health", Attendees: []string{"Kimi", "Rocky"}, Owner: "Kimi"},
-		Habit{Name: "bark", Type: "Nature", Attendees: []string{"Ruby", "Rocky"}, Owner: "Rocky"},
-		Habit{Name: "Blockchain", Type: "Learning", Attendees: []string{"Kimi", "Ruby", "Rocky"}, Owner: "Kimi"},
+		if err := putHabitIndex(ctx, ownerHabitIndex, entry.Owner, habitID); err != nil {
+			return err
+		}
+		if err := putHabitIndex(ctx, typeHabitIndex, entry.Type, habitID); err != nil {
+			return err
+		}
 	}
 
 	people := []Person{
-		Person{Name: "Kimi", Age: 29},
-		Person{Name: "Kathy", Age: 28},
-		Person{Name: "Ruby", Age: 5},
-		Person{Name: "Rocky", Age: 3},
+		{Name: "Kimi", Age: 29},
+		{Name: "Kathy", Age: 28},
+		{Name: "Ruby", Age: 5},
+		{Name: "Rocky", Age: 3},
 	}
 
-	i := 0
-	for i < len(habits) {
-		habitAsBytes, _ := json.Marshal(habits[i])
-		APIstub.PutState("HABIT"+strconv.Itoa(i), habitAsBytes)
-		i = i + 1
-	}
+	for i, person := range people {
+		personAsBytes, err := json.Marshal(person)
+		if err != nil {
+			return err
+		}
 
-	j := 0
-	for j < len(people) {
-		personAsBytes, _ := json.Marshal(people[j])
-		APIstub.PutState("PERSON"+strconv.Itoa(j), personAsBytes)
-		j = j + 1
+		err = ctx.GetStub().PutState(fmt.Sprintf("PERSON%d", i), personAsBytes)
+		if err != nil {
+			return fmt.Errorf("failed to put person to world state: %s", err.Error())
+		}
 	}
 
-	return shim.Success(nil)
+	return nil
 }
 
-func (s *SmartContract) createHabit(APIstub shim.ChaincodeStubInterface, args []string) sc.Response {
+// CreateHabit adds a new habit to the world state with the given details.
+// The caller's enrollment certificate must carry the habit.creator=true
+// attribute.
+func (s *SmartContract) CreateHabit(ctx contractapi.TransactionContextInterface, habitID string, name string, habitType string, attendee string, owner string) error {
+	if err := authorizeHabitCreation(ctx); err != nil {
+		return err
+	}
 
-	if len(args) != 5 {
-		return shim.Error("Incorrect number of arguments. Expecting 5")
+	exists, err := s.habitExists(ctx, habitID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("the habit %s already exists", habitID)
+	}
+
+	habit := Habit{Name: name, Type: habitType, Owner: owner}
+
+	habitAsBytes, err := json.Marshal(habit)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.GetStub().PutState(habitID, habitAsBytes); err != nil {
+		return err
 	}
 
-	attendees := []string{args[3]}
-	var habit = Habit{Name: args[1], Type: args[2], Attendees: attendees, Owner: args[4]}
+	detailsAsBytes, err := json.Marshal(HabitPrivateDetails{Attendees: []string{attendee}})
+	if err != nil {
+		return err
+	}
+	if err := ctx.GetStub().PutPrivateData(habitAttendeesCollection, habitID, detailsAsBytes); err != nil {
+		return err
+	}
 
-	habitAsBytes, _ := json.Marshal(habit)
-	APIstub.PutState(args[0], habitAsBytes)
+	if err := putHabitIndex(ctx, ownerHabitIndex, owner, habitID); err != nil {
+		return err
+	}
+	if err := putHabitIndex(ctx, typeHabitIndex, habitType, habitID); err != nil {
+		return err
+	}
 
-	return shim.Success(nil)
+	return emitHabitEvent(ctx, habitCreatedEvent, habitID, "", owner)
 }
 
-func (s *SmartContract) queryAllHabits(APIstub shim.ChaincodeStubInterface) sc.Response {
+// QueryHabit returns the habit stored in the world state with the given id
+func (s *SmartContract) QueryHabit(ctx contractapi.TransactionContextInterface, habitID string) (*Habit, error) {
+	habitAsBytes, err := ctx.GetStub().GetState(habitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read habit %s: %s", habitID, err.Error())
+	}
+	if habitAsBytes == nil {
+		return nil, fmt.Errorf("the habit %s does not exist", habitID)
+	}
+
+	habit := new(Habit)
+	if err := json.Unmarshal(habitAsBytes, habit); err != nil {
+		return nil, err
+	}
 
+	return habit, nil
+}
+
+// QueryAllHabits returns all habits found in the world state
+func (s *SmartContract) QueryAllHabits(ctx contractapi.TransactionContextInterface) ([]*Habit, error) {
 	startKey := "HABIT0"
 	endKey := "HABIT999"
 
-	resultsIterator, err := APIstub.GetStateByRange(startKey, endKey)
+	resultsIterator, err := ctx.GetStub().GetStateByRange(startKey, endKey)
 	if err != nil {
-		return shim.Error(err.Error())
+		return nil, err
 	}
 	defer resultsIterator.Close()
 
-	// buffer is a JSON array containing QueryResults
-	var buffer bytes.Buffer
-	buffer.WriteString("[")
-
-	bArrayMemberAlreadyWritten := false
+	var habits []*Habit
 	for resultsIterator.HasNext() {
 		queryResponse, err := resultsIterator.Next()
 		if err != nil {
-			return shim.Error(err.Error())
+			return nil, err
 		}
-		// Add a comma before array members, suppress it for the first array member
-		if bArrayMemberAlreadyWritten == true {
-			buffer.WriteString(",")
+
+		habit := new(Habit)
+		if err := json.Unmarshal(queryResponse.Value, habit); err != nil {
+			return nil, err
 		}
-		buffer.WriteString("{\"Key\":")
-		buffer.WriteString("\"")
-		buffer.WriteString(queryResponse.Key)
-		buffer.WriteString("\"")
 
-		buffer.WriteString(", \"Record\":")
-		// Record is a JSON object, so we write as-is
-		buffer.WriteString(string(queryResponse.Value))
-		buffer.WriteString("}")
-		bArrayMemberAlreadyWritten = true
+		habits = append(habits, habit)
 	}
-	buffer.WriteString("]")
 
-	return shim.Success(buffer.Bytes())
+	return habits, nil
 }
 
-func (s *SmartContract) changeHabitOwner(APIstub shim.ChaincodeStubInterface, args []string) sc.Response {
+// ChangeHabitOwner updates the owner field of a habit with the given id in
+// the world state. Only the current owner may reassign ownership.
+func (s *SmartContract) ChangeHabitOwner(ctx contractapi.TransactionContextInterface, habitID string, newOwner string) error {
+	habit, err := s.QueryHabit(ctx, habitID)
+	if err != nil {
+		return err
+	}
 
-	if len(args) != 2 {
-		return shim.Error("Incorrect number of arguments. Expecting 2")
+	if err := authorizeOwnerChange(ctx, habit); err != nil {
+		return err
 	}
 
-	habitAsBytes, _ := APIstub.GetState(args[0])
-	habit := Habit{}
+	previousOwner := habit.Owner
+	habit.Owner = newOwner
+
+	habitAsBytes, err := json.Marshal(habit)
+	if err != nil {
+		return err
+	}
 
-	json.Unmarshal(habitAsBytes, &habit)
-	habit.Owner = args[1]
+	if err := ctx.GetStub().PutState(habitID, habitAsBytes); err != nil {
+		return err
+	}
 
-	habitAsBytes, _ = json.Marshal(habit)
-	APIstub.PutState(args[0], habitAsBytes)
+	if err := deleteHabitIndex(ctx, ownerHabitIndex, previousOwner, habitID); err != nil {
+		return err
+	}
+	if err := putHabitIndex(ctx, ownerHabitIndex, newOwner, habitID); err != nil {
+		return err
+	}
 
-	return shim.Success(nil)
+	return emitHabitEvent(ctx, habitOwnerChangedEvent, habitID, previousOwner, newOwner)
 }
 
-func (s *SmartContract) changeHabitAttendees(APIstub shim.ChaincodeStubInterface, args []string) sc.Response {
+// ChangeHabitAttendees appends a new attendee to a habit with the given id.
+// Only the owner or an existing attendee may add someone. The updated
+// attendee list is written to the habitAttendeesCollection private data
+// collection; the chaincode event emitted for this change carries only a
+// hex-encoded hash of the private details before and after the change, since
+// events are broadcast to every peer on the channel regardless of private
+// data collection membership.
+func (s *SmartContract) ChangeHabitAttendees(ctx contractapi.TransactionContextInterface, habitID string, newAttendee string) error {
+	habit, err := s.QueryHabit(ctx, habitID)
+	if err != nil {
+		return err
+	}
 
-	if len(args) != 2 {
-		return shim.Error("Incorrect number of arguments. Expecting 2")
+	details, err := s.ReadHabitPrivateDetails(ctx, habitID)
+	if err != nil {
+		return err
 	}
 
-	habitAsBytes, _ := APIstub.GetState(args[0])
-	habit := Habit{}
+	if err := authorizeAttendeeChange(ctx, habit, details.Attendees); err != nil {
+		return err
+	}
 
-	json.Unmarshal(habitAsBytes, &habit)
+	previousHash, err := ctx.GetStub().GetPrivateDataHash(habitAttendeesCollection, habitID)
+	if err != nil {
+		return err
+	}
 
-	attendees := append(habit.Attendees, args[1])
-	habit.Attendees = attendees
+	details.Attendees = append(details.Attendees, newAttendee)
 
-	habitAsBytes, _ = json.Marshal(habit)
-	APIstub.PutState(args[0], habitAsBytes)
+	detailsAsBytes, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
 
-	return shim.Success(nil)
-}
+	if err := ctx.GetStub().PutPrivateData(habitAttendeesCollection, habitID, detailsAsBytes); err != nil {
+		return err
+	}
+
+	// GetPrivateDataHash does not see writes made earlier in the same
+	// transaction, so hash the new value directly, the same way the peer does.
+	currentHash := sha256.Sum256(detailsAsBytes)
 
-// The main function is only relevant in unit test mode. Only included here for completeness.
-func main() {
+	return emitHabitEvent(ctx, habitAttendeeAddedEvent, habitID, hex.EncodeToString(previousHash), hex.EncodeToString(currentHash[:]))
+}
 
-	// Create a new Smart Contract
-	err := shim.Start(new(SmartContract))
+// habitExists returns true when a habit with the given id already exists in the world state
+func (s *SmartContract) habitExists(ctx contractapi.TransactionContextInterface, habitID string) (bool, error) {
+	habitAsBytes, err := ctx.GetStub().GetState(habitID)
 	if err != nil {
-		fmt.Printf("Error creating new Smart Contract: %s", err)
+		return false, fmt.Errorf("failed to read habit %s: %s", habitID, err.Error())
 	}
+
+	return habitAsBytes != nil, nil
 }
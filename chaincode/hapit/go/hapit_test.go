@@ -0,0 +1,161 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func creatorAttrs(t *testing.T) []byte {
+	return newIdentity(t, "Kathy", map[string]string{habitCreatorAttribute: "true"})
+}
+
+func TestCreateAndQueryHabit(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	err := contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy")
+	require.NoError(t, err)
+
+	habit, err := contract.QueryHabit(ctx, "HABIT0")
+	require.NoError(t, err)
+	require.Equal(t, "Running", habit.Name)
+	require.Equal(t, "Health", habit.Type)
+	require.Equal(t, "Kathy", habit.Owner)
+
+	details, err := contract.ReadHabitPrivateDetails(ctx, "HABIT0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Ruby"}, details.Attendees)
+}
+
+func TestCreateHabitRequiresCreatorAttribute(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(newIdentity(t, "Kathy", nil))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	err := contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy")
+	require.Error(t, err)
+	require.IsType(t, &PermissionError{}, err)
+}
+
+func TestCreateHabitAlreadyExists(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+	err := contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy")
+	require.Error(t, err)
+}
+
+func TestQueryHabitNotFound(t *testing.T) {
+	stub := NewMockStub()
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	_, err := contract.QueryHabit(ctx, "HABIT0")
+	require.Error(t, err)
+}
+
+func TestQueryAllHabits(t *testing.T) {
+	stub := NewMockStub()
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.InitLedger(ctx))
+
+	habits, err := contract.QueryAllHabits(ctx)
+	require.NoError(t, err)
+	require.Len(t, habits, 5)
+}
+
+func TestChangeHabitOwner(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+	require.NoError(t, contract.ChangeHabitOwner(ctx, "HABIT0", "Ruby"))
+
+	habit, err := contract.QueryHabit(ctx, "HABIT0")
+	require.NoError(t, err)
+	require.Equal(t, "Ruby", habit.Owner)
+}
+
+func TestChangeHabitOwnerRejectsNonOwner(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+
+	stub.SetCreator(newIdentity(t, "Ruby", nil))
+	err := contract.ChangeHabitOwner(ctx, "HABIT0", "Ruby")
+	require.Error(t, err)
+	require.IsType(t, &PermissionError{}, err)
+}
+
+func TestChangeHabitAttendees(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+	require.NoError(t, contract.ChangeHabitAttendees(ctx, "HABIT0", "Kimi"))
+
+	details, err := contract.ReadHabitPrivateDetails(ctx, "HABIT0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Ruby", "Kimi"}, details.Attendees)
+}
+
+func TestChangeHabitAttendeesAllowsExistingAttendee(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+
+	stub.SetCreator(newIdentity(t, "Ruby", nil))
+	require.NoError(t, contract.ChangeHabitAttendees(ctx, "HABIT0", "Kimi"))
+}
+
+func TestChangeHabitAttendeesRejectsStranger(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+
+	stub.SetCreator(newIdentity(t, "Rocky", nil))
+	err := contract.ChangeHabitAttendees(ctx, "HABIT0", "Kimi")
+	require.Error(t, err)
+	require.IsType(t, &PermissionError{}, err)
+}
@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+)
+
+// HistoryEntry describes one past modification of a habit, as reported by
+// the ledger's history database.
+type HistoryEntry struct {
+	TxID      string `json:"txId"`
+	Timestamp string `json:"timestamp"`
+	IsDelete  bool   `json:"isDelete"`
+	Value     *Habit `json:"value,omitempty"`
+}
+
+// GetHabitHistory returns every recorded modification of a habit, most
+// recent first, as reported by GetHistoryForKey.
+func (s *SmartContract) GetHabitHistory(ctx contractapi.TransactionContextInterface, habitID string) ([]*HistoryEntry, error) {
+	resultsIterator, err := ctx.GetStub().GetHistoryForKey(habitID)
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var history []*HistoryEntry
+	for resultsIterator.HasNext() {
+		modification, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		entry, err := newHistoryEntry(modification)
+		if err != nil {
+			return nil, err
+		}
+
+		history = append(history, entry)
+	}
+
+	return history, nil
+}
+
+// GetHabitAtTime returns the state of a habit as of the given RFC3339
+// timestamp, by scanning its history for the most recent modification that
+// is not after that time.
+func (s *SmartContract) GetHabitAtTime(ctx contractapi.TransactionContextInterface, habitID string, rfc3339 string) (*Habit, error) {
+	asOf, err := time.Parse(time.RFC3339, rfc3339)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp %s: %s", rfc3339, err.Error())
+	}
+
+	resultsIterator, err := ctx.GetStub().GetHistoryForKey(habitID)
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	for resultsIterator.HasNext() {
+		modification, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if modification.Timestamp.AsTime().After(asOf) {
+			continue
+		}
+
+		if modification.IsDelete {
+			return nil, fmt.Errorf("the habit %s had been deleted as of %s", habitID, rfc3339)
+		}
+
+		habit := new(Habit)
+		if err := json.Unmarshal(modification.Value, habit); err != nil {
+			return nil, err
+		}
+		return habit, nil
+	}
+
+	return nil, fmt.Errorf("no history found for habit %s as of %s", habitID, rfc3339)
+}
+
+func newHistoryEntry(modification *queryresult.KeyModification) (*HistoryEntry, error) {
+	entry := &HistoryEntry{
+		TxID:      modification.TxId,
+		Timestamp: modification.Timestamp.AsTime().UTC().Format(time.RFC3339),
+		IsDelete:  modification.IsDelete,
+	}
+
+	if !modification.IsDelete {
+		habit := new(Habit)
+		if err := json.Unmarshal(modification.Value, habit); err != nil {
+			return nil, err
+		}
+		entry.Value = habit
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHabitHistory(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+	require.NoError(t, contract.ChangeHabitOwner(ctx, "HABIT0", "Ruby"))
+
+	history, err := contract.GetHabitHistory(ctx, "HABIT0")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	// Most recent modification is returned first.
+	require.Equal(t, "Ruby", history[0].Value.Owner)
+	require.Equal(t, "Kathy", history[1].Value.Owner)
+	require.False(t, history[0].IsDelete)
+}
+
+func TestGetHabitAtTime(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+
+	history, err := contract.GetHabitHistory(ctx, "HABIT0")
+	require.NoError(t, err)
+	createdAt := history[0].Timestamp
+
+	require.NoError(t, contract.ChangeHabitOwner(ctx, "HABIT0", "Ruby"))
+
+	habitAtCreation, err := contract.GetHabitAtTime(ctx, "HABIT0", createdAt)
+	require.NoError(t, err)
+	require.Equal(t, "Kathy", habitAtCreation.Owner)
+
+	habit, err := contract.QueryHabit(ctx, "HABIT0")
+	require.NoError(t, err)
+	require.Equal(t, "Ruby", habit.Owner)
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/stretchr/testify/require"
+)
+
+// attributeOID is the fabric-ca extension OID that carries a caller's
+// attribute-certificate attributes on its enrollment certificate.
+var attributeOID = asn1.ObjectIdentifier{1, 2, 3, 4, 5, 6, 7, 8, 1}
+
+type certAttrs struct {
+	Attrs map[string]string `json:"attrs"`
+}
+
+// newIdentity builds a serialized MSP identity for an enrollment certificate
+// with the given subject common name and fabric-ca attributes, for use as
+// the value returned by MockStub.GetCreator.
+func newIdentity(t *testing.T, commonName string, attrs map[string]string) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).Add(100 * 365 * 24 * time.Hour),
+	}
+
+	if len(attrs) > 0 {
+		attrsAsBytes, err := json.Marshal(certAttrs{Attrs: attrs})
+		require.NoError(t, err)
+		template.ExtraExtensions = append(template.ExtraExtensions, pkix.Extension{
+			Id:    attributeOID,
+			Value: attrsAsBytes,
+		})
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+
+	identity := &msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: certPEM}
+	identityAsBytes, err := proto.Marshal(identity)
+	require.NoError(t, err)
+
+	return identityAsBytes
+}
@@ -0,0 +1,48 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import "github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+// indexMarker is the value stored under each composite key; only the key
+// itself is meaningful, so the value is an empty marker byte as recommended
+// by the Fabric documentation.
+var indexMarker = []byte{0x00}
+
+// putHabitIndex writes a composite key entry linking an attribute value
+// (an owner or a type) back to a habit ID.
+func putHabitIndex(ctx contractapi.TransactionContextInterface, objectType, attributeValue, habitID string) error {
+	key, err := ctx.GetStub().CreateCompositeKey(objectType, []string{attributeValue, habitID})
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(key, indexMarker)
+}
+
+// deleteHabitIndex removes a previously written composite key entry.
+func deleteHabitIndex(ctx contractapi.TransactionContextInterface, objectType, attributeValue, habitID string) error {
+	key, err := ctx.GetStub().CreateCompositeKey(objectType, []string{attributeValue, habitID})
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().DelState(key)
+}
@@ -0,0 +1,389 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// minUnicodeRuneValue separates composite key segments, matching the
+// encoding used by the real peer so SplitCompositeKey round-trips.
+var minUnicodeRuneValue = string(rune(0))
+
+// MockStub is a minimal stand-in for shim.ChaincodeStubInterface that keeps
+// state in memory so handlers can be exercised without a peer.
+type MockStub struct {
+	shim.ChaincodeStubInterface
+	state        map[string][]byte
+	creator      []byte
+	history      map[string][]*queryresult.KeyModification
+	txSeq        int
+	privateState map[string]map[string][]byte
+	transient    map[string][]byte
+	eventName    string
+	eventPayload []byte
+}
+
+func NewMockStub() *MockStub {
+	return &MockStub{
+		state:        make(map[string][]byte),
+		history:      make(map[string][]*queryresult.KeyModification),
+		privateState: make(map[string]map[string][]byte),
+	}
+}
+
+// SetTransient sets the transient map returned by GetTransient, so handlers
+// that read sensitive arguments out of band can be exercised.
+func (m *MockStub) SetTransient(transient map[string][]byte) {
+	m.transient = transient
+}
+
+func (m *MockStub) GetTransient() (map[string][]byte, error) {
+	return m.transient, nil
+}
+
+func (m *MockStub) GetTxID() string {
+	return fmt.Sprintf("tx%d", m.txSeq+1)
+}
+
+func (m *MockStub) GetTxTimestamp() (*timestamppb.Timestamp, error) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(m.txSeq+1) * time.Minute)
+	return timestamppb.New(at), nil
+}
+
+func (m *MockStub) SetEvent(name string, payload []byte) error {
+	m.eventName = name
+	m.eventPayload = payload
+	return nil
+}
+
+// Event returns the name and payload passed to the last SetEvent call, for
+// tests to assert on.
+func (m *MockStub) Event() (string, []byte) {
+	return m.eventName, m.eventPayload
+}
+
+func (m *MockStub) PutPrivateData(collection, key string, value []byte) error {
+	if m.privateState[collection] == nil {
+		m.privateState[collection] = make(map[string][]byte)
+	}
+	m.privateState[collection][key] = value
+	return nil
+}
+
+func (m *MockStub) GetPrivateData(collection, key string) ([]byte, error) {
+	return m.privateState[collection][key], nil
+}
+
+func (m *MockStub) GetPrivateDataHash(collection, key string) ([]byte, error) {
+	value, ok := m.privateState[collection][key]
+	if !ok {
+		return nil, nil
+	}
+	hash := sha256.Sum256(value)
+	return hash[:], nil
+}
+
+func (m *MockStub) GetPrivateDataQueryResult(collection, query string) (shim.StateQueryIteratorInterface, error) {
+	var selector map[string]interface{}
+	if err := json.Unmarshal([]byte(query), &selector); err != nil {
+		return nil, err
+	}
+	if nested, ok := selector["selector"].(map[string]interface{}); ok {
+		selector = nested
+	}
+
+	keys := make([]string, 0, len(m.privateState[collection]))
+	for key := range m.privateState[collection] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	it := &mockStateIterator{}
+	for _, key := range keys {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(m.privateState[collection][key], &doc); err != nil {
+			continue
+		}
+		if matchesSelector(doc, selector) {
+			it.results = append(it.results, &queryresult.KV{Key: key, Value: m.privateState[collection][key]})
+		}
+	}
+	return it, nil
+}
+
+// recordHistory prepends a modification of key to its history so the most
+// recent change is always returned first by GetHistoryForKey, matching the
+// real peer's ordering.
+func (m *MockStub) recordHistory(key string, value []byte, isDelete bool) {
+	m.txSeq++
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(m.txSeq) * time.Minute)
+	modification := &queryresult.KeyModification{
+		TxId:      fmt.Sprintf("tx%d", m.txSeq),
+		Timestamp: timestamppb.New(at),
+		Value:     value,
+		IsDelete:  isDelete,
+	}
+	m.history[key] = append([]*queryresult.KeyModification{modification}, m.history[key]...)
+}
+
+// SetCreator sets the serialized identity returned by GetCreator, so handlers
+// relying on the cid package can be exercised as a particular caller.
+func (m *MockStub) SetCreator(creator []byte) {
+	m.creator = creator
+}
+
+func (m *MockStub) GetCreator() ([]byte, error) {
+	return m.creator, nil
+}
+
+func (m *MockStub) GetState(key string) ([]byte, error) {
+	return m.state[key], nil
+}
+
+func (m *MockStub) PutState(key string, value []byte) error {
+	m.state[key] = value
+	m.recordHistory(key, value, false)
+	return nil
+}
+
+func (m *MockStub) DelState(key string) error {
+	delete(m.state, key)
+	m.recordHistory(key, nil, true)
+	return nil
+}
+
+func (m *MockStub) GetHistoryForKey(key string) (shim.HistoryQueryIteratorInterface, error) {
+	return &mockHistoryIterator{results: m.history[key]}, nil
+}
+
+func (m *MockStub) GetStateByRange(startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
+	keys := make([]string, 0, len(m.state))
+	for key := range m.state {
+		if key >= startKey && key < endKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	it := &mockStateIterator{}
+	for _, key := range keys {
+		it.results = append(it.results, &queryresult.KV{Key: key, Value: m.state[key]})
+	}
+	return it, nil
+}
+
+// mockStateIterator implements shim.StateQueryIteratorInterface over an
+// in-memory slice of results collected ahead of time by MockStub.
+type mockStateIterator struct {
+	results []*queryresult.KV
+	index   int
+}
+
+func (it *mockStateIterator) HasNext() bool {
+	return it.index < len(it.results)
+}
+
+func (it *mockStateIterator) Next() (*queryresult.KV, error) {
+	result := it.results[it.index]
+	it.index++
+	return result, nil
+}
+
+func (it *mockStateIterator) Close() error {
+	return nil
+}
+
+// mockHistoryIterator implements shim.HistoryQueryIteratorInterface over an
+// in-memory slice of modifications collected ahead of time by MockStub.
+type mockHistoryIterator struct {
+	results []*queryresult.KeyModification
+	index   int
+}
+
+func (it *mockHistoryIterator) HasNext() bool {
+	return it.index < len(it.results)
+}
+
+func (it *mockHistoryIterator) Next() (*queryresult.KeyModification, error) {
+	result := it.results[it.index]
+	it.index++
+	return result, nil
+}
+
+func (it *mockHistoryIterator) Close() error {
+	return nil
+}
+
+func (m *MockStub) CreateCompositeKey(objectType string, attributes []string) (string, error) {
+	return createCompositeKey(objectType, attributes)
+}
+
+func createCompositeKey(objectType string, attributes []string) (string, error) {
+	key := minUnicodeRuneValue + objectType + minUnicodeRuneValue
+	for _, attribute := range attributes {
+		key += attribute + minUnicodeRuneValue
+	}
+	return key, nil
+}
+
+func (m *MockStub) SplitCompositeKey(compositeKey string) (string, []string, error) {
+	segments := strings.Split(compositeKey, minUnicodeRuneValue)
+	if len(segments) < 2 {
+		return "", nil, fmt.Errorf("invalid composite key: %s", compositeKey)
+	}
+	// segments[0] is empty (the key starts with the separator) and the
+	// trailing segment is empty too (the key ends with the separator).
+	parts := segments[1 : len(segments)-1]
+	return parts[0], parts[1:], nil
+}
+
+func (m *MockStub) GetStateByPartialCompositeKey(objectType string, attributes []string) (shim.StateQueryIteratorInterface, error) {
+	prefix, err := createCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(m.state))
+	for key := range m.state {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	it := &mockStateIterator{}
+	for _, key := range keys {
+		it.results = append(it.results, &queryresult.KV{Key: key, Value: m.state[key]})
+	}
+	return it, nil
+}
+
+// GetQueryResultWithPagination evaluates a CouchDB-style Mango selector
+// against every stored document, comparing top-level fields either by scalar
+// equality or with the $elemMatch operator handled by matchesOperator. The
+// bookmark is ignored and no bookmark is returned. It is a test convenience,
+// not a faithful CouchDB query engine.
+func (m *MockStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	var selector map[string]interface{}
+	if err := json.Unmarshal([]byte(query), &selector); err != nil {
+		return nil, nil, err
+	}
+	if nested, ok := selector["selector"].(map[string]interface{}); ok {
+		selector = nested
+	}
+
+	keys := make([]string, 0, len(m.state))
+	for key := range m.state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	it := &mockStateIterator{}
+	for _, key := range keys {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(m.state[key], &doc); err != nil {
+			continue
+		}
+		if matchesSelector(doc, selector) {
+			it.results = append(it.results, &queryresult.KV{Key: key, Value: m.state[key]})
+		}
+	}
+
+	if pageSize > 0 && int32(len(it.results)) > pageSize {
+		it.results = it.results[:pageSize]
+	}
+
+	metadata := &peer.QueryResponseMetadata{FetchedRecordsCount: int32(len(it.results))}
+	return it, metadata, nil
+}
+
+func matchesSelector(doc map[string]interface{}, selector map[string]interface{}) bool {
+	for field, want := range selector {
+		got, ok := doc[field]
+		if !ok {
+			return false
+		}
+
+		switch want := want.(type) {
+		case string, float64, bool:
+			if got != want {
+				return false
+			}
+		case map[string]interface{}:
+			if !matchesOperator(got, want) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchesOperator evaluates the small subset of CouchDB Mango operators this
+// mock needs to exercise: $elemMatch over an array field, with an inner $eq.
+func matchesOperator(got interface{}, operator map[string]interface{}) bool {
+	elemMatch, ok := operator["$elemMatch"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	want, ok := elemMatch["$eq"]
+	if !ok {
+		return false
+	}
+
+	elements, ok := got.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, element := range elements {
+		if element == want {
+			return true
+		}
+	}
+	return false
+}
+
+// MockTransactionContext is a minimal stand-in for
+// contractapi.TransactionContextInterface backed by a MockStub.
+type MockTransactionContext struct {
+	contractapi.TransactionContext
+	stub *MockStub
+}
+
+func NewMockTransactionContext(stub *MockStub) *MockTransactionContext {
+	ctx := new(MockTransactionContext)
+	ctx.stub = stub
+	return ctx
+}
+
+func (ctx *MockTransactionContext) GetStub() shim.ChaincodeStubInterface {
+	return ctx.stub
+}
@@ -0,0 +1,121 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// CreatePerson adds a new person to the world state with the given details
+func (s *SmartContract) CreatePerson(ctx contractapi.TransactionContextInterface, personID string, name string, age int) error {
+	exists, err := s.personExists(ctx, personID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("the person %s already exists", personID)
+	}
+
+	person := Person{Name: name, Age: age}
+
+	personAsBytes, err := json.Marshal(person)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(personID, personAsBytes)
+}
+
+// QueryPerson returns the person stored in the world state with the given id
+func (s *SmartContract) QueryPerson(ctx contractapi.TransactionContextInterface, personID string) (*Person, error) {
+	personAsBytes, err := ctx.GetStub().GetState(personID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read person %s: %s", personID, err.Error())
+	}
+	if personAsBytes == nil {
+		return nil, fmt.Errorf("the person %s does not exist", personID)
+	}
+
+	person := new(Person)
+	if err := json.Unmarshal(personAsBytes, person); err != nil {
+		return nil, err
+	}
+
+	return person, nil
+}
+
+// QueryAllPeople returns all people found in the world state
+func (s *SmartContract) QueryAllPeople(ctx contractapi.TransactionContextInterface) ([]*Person, error) {
+	startKey := "PERSON0"
+	endKey := "PERSON999"
+
+	resultsIterator, err := ctx.GetStub().GetStateByRange(startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var people []*Person
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		person := new(Person)
+		if err := json.Unmarshal(queryResponse.Value, person); err != nil {
+			return nil, err
+		}
+
+		people = append(people, person)
+	}
+
+	return people, nil
+}
+
+// UpdatePerson updates the name and age of a person with the given id in the world state
+func (s *SmartContract) UpdatePerson(ctx contractapi.TransactionContextInterface, personID string, name string, age int) error {
+	_, err := s.QueryPerson(ctx, personID)
+	if err != nil {
+		return err
+	}
+
+	person := Person{Name: name, Age: age}
+
+	personAsBytes, err := json.Marshal(person)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(personID, personAsBytes)
+}
+
+// personExists returns true when a person with the given id already exists in the world state
+func (s *SmartContract) personExists(ctx contractapi.TransactionContextInterface, personID string) (bool, error) {
+	personAsBytes, err := ctx.GetStub().GetState(personID)
+	if err != nil {
+		return false, fmt.Errorf("failed to read person %s: %s", personID, err.Error())
+	}
+
+	return personAsBytes != nil, nil
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndQueryPerson(t *testing.T) {
+	stub := NewMockStub()
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreatePerson(ctx, "PERSON0", "Kimi", 29))
+
+	person, err := contract.QueryPerson(ctx, "PERSON0")
+	require.NoError(t, err)
+	require.Equal(t, "Kimi", person.Name)
+	require.Equal(t, 29, person.Age)
+}
+
+func TestCreatePersonAlreadyExists(t *testing.T) {
+	stub := NewMockStub()
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreatePerson(ctx, "PERSON0", "Kimi", 29))
+	err := contract.CreatePerson(ctx, "PERSON0", "Kimi", 29)
+	require.Error(t, err)
+}
+
+func TestQueryAllPeople(t *testing.T) {
+	stub := NewMockStub()
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.InitLedger(ctx))
+
+	people, err := contract.QueryAllPeople(ctx)
+	require.NoError(t, err)
+	require.Len(t, people, 4)
+}
+
+func TestUpdatePerson(t *testing.T) {
+	stub := NewMockStub()
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreatePerson(ctx, "PERSON0", "Kimi", 29))
+	require.NoError(t, contract.UpdatePerson(ctx, "PERSON0", "Kimi", 30))
+
+	person, err := contract.QueryPerson(ctx, "PERSON0")
+	require.NoError(t, err)
+	require.Equal(t, 30, person.Age)
+}
+
+func TestUpdatePersonNotFound(t *testing.T) {
+	stub := NewMockStub()
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	err := contract.UpdatePerson(ctx, "PERSON0", "Kimi", 30)
+	require.Error(t, err)
+}
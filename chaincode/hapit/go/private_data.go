@@ -0,0 +1,147 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// habitAttendeesCollection is the private data collection that holds the
+// sensitive parts of a habit: who attends it, and any notes about it. Its
+// membership policy is defined in collections_config.json.
+const habitAttendeesCollection = "habitAttendeesCollection"
+
+// habitPropertiesTransientKey is the key under which callers pass the
+// private part of a habit through the transaction's transient map, so it
+// never appears in the public transaction proposal or the block itself.
+const habitPropertiesTransientKey = "habit_properties"
+
+// HabitPrivateDetails holds the sensitive fields of a habit that are stored
+// only in the habitAttendeesCollection, never on the public ledger.
+type HabitPrivateDetails struct {
+	Attendees []string `json:"attendees"`
+	Notes     string   `json:"notes,omitempty"`
+}
+
+// CreateHabitPrivate creates a habit whose public fields are taken from the
+// arguments and whose private details (attendees and notes) are read from
+// the habit_properties key of the transaction's transient map, so that
+// sensitive data is never recorded in the transaction itself.
+func (s *SmartContract) CreateHabitPrivate(ctx contractapi.TransactionContextInterface, habitID string, name string, habitType string, owner string) error {
+	if err := authorizeHabitCreation(ctx); err != nil {
+		return err
+	}
+
+	exists, err := s.habitExists(ctx, habitID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("the habit %s already exists", habitID)
+	}
+
+	propertiesAsBytes, err := habitPropertiesFromTransient(ctx)
+	if err != nil {
+		return err
+	}
+
+	habit := Habit{Name: name, Type: habitType, Owner: owner}
+	habitAsBytes, err := json.Marshal(habit)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.GetStub().PutState(habitID, habitAsBytes); err != nil {
+		return err
+	}
+
+	if err := ctx.GetStub().PutPrivateData(habitAttendeesCollection, habitID, propertiesAsBytes); err != nil {
+		return err
+	}
+
+	if err := putHabitIndex(ctx, ownerHabitIndex, owner, habitID); err != nil {
+		return err
+	}
+	return putHabitIndex(ctx, typeHabitIndex, habitType, habitID)
+}
+
+// ReadHabitPrivateDetails returns the attendees and notes of a habit from
+// the habitAttendeesCollection. It only succeeds on a peer belonging to an
+// organization in the collection's membership policy.
+func (s *SmartContract) ReadHabitPrivateDetails(ctx contractapi.TransactionContextInterface, habitID string) (*HabitPrivateDetails, error) {
+	detailsAsBytes, err := ctx.GetStub().GetPrivateData(habitAttendeesCollection, habitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private details for habit %s: %s", habitID, err.Error())
+	}
+	if detailsAsBytes == nil {
+		return nil, fmt.Errorf("no private details found for habit %s", habitID)
+	}
+
+	details := new(HabitPrivateDetails)
+	if err := json.Unmarshal(detailsAsBytes, details); err != nil {
+		return nil, err
+	}
+
+	return details, nil
+}
+
+// VerifyHabitPrivate proves that a candidate attendee list and notes, passed
+// through the habit_properties transient field, match what is actually
+// stored in the habitAttendeesCollection for the given habit - without
+// requiring the caller's peer to have access to the collection itself. It
+// does this by comparing hashes, using GetPrivateDataHash.
+func (s *SmartContract) VerifyHabitPrivate(ctx contractapi.TransactionContextInterface, habitID string) (bool, error) {
+	candidateAsBytes, err := habitPropertiesFromTransient(ctx)
+	if err != nil {
+		return false, err
+	}
+	candidateHash := sha256.Sum256(candidateAsBytes)
+
+	onChainHash, err := ctx.GetStub().GetPrivateDataHash(habitAttendeesCollection, habitID)
+	if err != nil {
+		return false, err
+	}
+	if len(onChainHash) == 0 {
+		return false, fmt.Errorf("no private data hash found for habit %s", habitID)
+	}
+
+	return bytes.Equal(candidateHash[:], onChainHash), nil
+}
+
+// habitPropertiesFromTransient reads the habit_properties entry out of the
+// transaction's transient map.
+func habitPropertiesFromTransient(ctx contractapi.TransactionContextInterface) ([]byte, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, err
+	}
+
+	propertiesAsBytes, ok := transientMap[habitPropertiesTransientKey]
+	if !ok {
+		return nil, fmt.Errorf("the %s key was not found in the transient map", habitPropertiesTransientKey)
+	}
+
+	return propertiesAsBytes, nil
+}
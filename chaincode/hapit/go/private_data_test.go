@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHabitPrivate(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	propertiesAsBytes, err := json.Marshal(HabitPrivateDetails{Attendees: []string{"Ruby"}, Notes: "knee-friendly pace"})
+	require.NoError(t, err)
+	stub.SetTransient(map[string][]byte{habitPropertiesTransientKey: propertiesAsBytes})
+
+	require.NoError(t, contract.CreateHabitPrivate(ctx, "HABIT0", "Running", "Health", "Kathy"))
+
+	habit, err := contract.QueryHabit(ctx, "HABIT0")
+	require.NoError(t, err)
+	require.Equal(t, "Running", habit.Name)
+
+	details, err := contract.ReadHabitPrivateDetails(ctx, "HABIT0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Ruby"}, details.Attendees)
+	require.Equal(t, "knee-friendly pace", details.Notes)
+}
+
+func TestVerifyHabitPrivate(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+
+	matchingProperties, err := json.Marshal(HabitPrivateDetails{Attendees: []string{"Ruby"}})
+	require.NoError(t, err)
+	stub.SetTransient(map[string][]byte{habitPropertiesTransientKey: matchingProperties})
+
+	ok, err := contract.VerifyHabitPrivate(ctx, "HABIT0")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	mismatchedProperties, err := json.Marshal(HabitPrivateDetails{Attendees: []string{"Rocky"}})
+	require.NoError(t, err)
+	stub.SetTransient(map[string][]byte{habitPropertiesTransientKey: mismatchedProperties})
+
+	ok, err = contract.VerifyHabitPrivate(ctx, "HABIT0")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,163 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Composite key object types used to index habits by owner and type so the
+// same lookups work on LevelDB, not just CouchDB. Attendees are no longer
+// indexed this way since they moved to the habitAttendeesCollection private
+// data collection; see QueryHabitsByAttendee.
+const (
+	ownerHabitIndex = "owner~habit"
+	typeHabitIndex  = "type~habit"
+)
+
+// PaginatedQueryResult wraps the result of a rich CouchDB query together
+// with the pagination metadata needed to fetch the next page.
+type PaginatedQueryResult struct {
+	Records             []*Habit `json:"records"`
+	FetchedRecordsCount int32    `json:"fetchedRecordsCount"`
+	Bookmark            string   `json:"bookmark"`
+}
+
+// QueryHabitsByOwner returns every habit owned by the given person, using the
+// owner~habit composite key index so the lookup works under LevelDB too.
+func (s *SmartContract) QueryHabitsByOwner(ctx contractapi.TransactionContextInterface, owner string) ([]*Habit, error) {
+	return s.queryHabitsByCompositeKey(ctx, ownerHabitIndex, []string{owner})
+}
+
+// QueryHabitsByType returns every habit of the given type, using the
+// type~habit composite key index so the lookup works under LevelDB too.
+func (s *SmartContract) QueryHabitsByType(ctx contractapi.TransactionContextInterface, habitType string) ([]*Habit, error) {
+	return s.queryHabitsByCompositeKey(ctx, typeHabitIndex, []string{habitType})
+}
+
+// QueryHabitsByAttendee returns every habit the given person attends. The
+// attendee list lives in the habitAttendeesCollection private data
+// collection, so this runs a rich query against that collection instead of
+// a public composite key index; it only returns results on peers that
+// belong to an organization in the collection's policy.
+func (s *SmartContract) QueryHabitsByAttendee(ctx contractapi.TransactionContextInterface, attendee string) ([]*Habit, error) {
+	selector := fmt.Sprintf(`{"selector":{"attendees":{"$elemMatch":{"$eq":%q}}}}`, attendee)
+
+	resultsIterator, err := ctx.GetStub().GetPrivateDataQueryResult(habitAttendeesCollection, selector)
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var habits []*Habit
+	for resultsIterator.HasNext() {
+		result, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		habit, err := s.QueryHabit(ctx, result.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		habits = append(habits, habit)
+	}
+
+	return habits, nil
+}
+
+// queryHabitsByCompositeKey resolves the habit IDs indexed under the given
+// composite key prefix and reads each matching habit back from the ledger.
+func (s *SmartContract) queryHabitsByCompositeKey(ctx contractapi.TransactionContextInterface, objectType string, attributes []string) ([]*Habit, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var habits []*Habit
+	for resultsIterator.HasNext() {
+		result, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		_, compositeKeyParts, err := ctx.GetStub().SplitCompositeKey(result.Key)
+		if err != nil {
+			return nil, err
+		}
+		habitID := compositeKeyParts[len(compositeKeyParts)-1]
+
+		habit, err := s.QueryHabit(ctx, habitID)
+		if err != nil {
+			return nil, err
+		}
+
+		habits = append(habits, habit)
+	}
+
+	return habits, nil
+}
+
+// QueryHabits runs an arbitrary CouchDB selector against the habit documents
+// and returns a page of results together with a bookmark to fetch the next
+// page. It requires a CouchDB state database and is not available on LevelDB.
+func (s *SmartContract) QueryHabits(ctx contractapi.TransactionContextInterface, selectorJSON string, pageSize int32, bookmark string) (*PaginatedQueryResult, error) {
+	resultsIterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(selectorJSON, pageSize, bookmark)
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	habits, err := habitsFromIterator(resultsIterator)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaginatedQueryResult{
+		Records:             habits,
+		FetchedRecordsCount: metadata.FetchedRecordsCount,
+		Bookmark:            metadata.Bookmark,
+	}, nil
+}
+
+func habitsFromIterator(resultsIterator shim.StateQueryIteratorInterface) ([]*Habit, error) {
+	var habits []*Habit
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		habit := new(Habit)
+		if err := json.Unmarshal(queryResponse.Value, habit); err != nil {
+			return nil, err
+		}
+
+		habits = append(habits, habit)
+	}
+
+	return habits, nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setUpHabits(t *testing.T, ctx *MockTransactionContext, contract *SmartContract) {
+	t.Helper()
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT0", "Running", "Health", "Ruby", "Kathy"))
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT1", "Workout", "Health", "Rocky", "Kimi"))
+	require.NoError(t, contract.CreateHabit(ctx, "HABIT2", "English", "Learning", "Kathy", "Kathy"))
+}
+
+func TestQueryHabitsByOwner(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+	setUpHabits(t, ctx, contract)
+
+	habits, err := contract.QueryHabitsByOwner(ctx, "Kathy")
+	require.NoError(t, err)
+	require.Len(t, habits, 2)
+}
+
+func TestQueryHabitsByType(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+	setUpHabits(t, ctx, contract)
+
+	habits, err := contract.QueryHabitsByType(ctx, "Health")
+	require.NoError(t, err)
+	require.Len(t, habits, 2)
+}
+
+func TestQueryHabitsByAttendee(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+	setUpHabits(t, ctx, contract)
+
+	habits, err := contract.QueryHabitsByAttendee(ctx, "Kathy")
+	require.NoError(t, err)
+	require.Len(t, habits, 1)
+	require.Equal(t, "English", habits[0].Name)
+}
+
+func TestQueryHabitsByOwnerTracksOwnerChange(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+	setUpHabits(t, ctx, contract)
+
+	require.NoError(t, contract.ChangeHabitOwner(ctx, "HABIT0", "Ruby"))
+
+	kathysHabits, err := contract.QueryHabitsByOwner(ctx, "Kathy")
+	require.NoError(t, err)
+	require.Len(t, kathysHabits, 1)
+
+	rubysHabits, err := contract.QueryHabitsByOwner(ctx, "Ruby")
+	require.NoError(t, err)
+	require.Len(t, rubysHabits, 1)
+	require.Equal(t, "Running", rubysHabits[0].Name)
+}
+
+func TestQueryHabits(t *testing.T) {
+	stub := NewMockStub()
+	stub.SetCreator(creatorAttrs(t))
+	ctx := NewMockTransactionContext(stub)
+	contract := new(SmartContract)
+	setUpHabits(t, ctx, contract)
+
+	result, err := contract.QueryHabits(ctx, `{"selector":{"owner":"Kathy"}}`, 10, "")
+	require.NoError(t, err)
+	require.Len(t, result.Records, 2)
+	require.EqualValues(t, 2, result.FetchedRecordsCount)
+}